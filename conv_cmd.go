@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/crisecheverria/safeguard/llm"
+	"github.com/crisecheverria/safeguard/pkg/conversations"
+)
+
+// runConvCommand handles the `safeguard conv <subcommand>` family, letting
+// users revisit a saved analysis session without regenerating its diff.
+func runConvCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: safeguard conv list|view <id>|reply <id> <msg>|rm <id>|branch <id>")
+		os.Exit(1)
+	}
+
+	store, err := conversations.Open()
+	if err != nil {
+		fmt.Printf("Error opening conversation store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "list":
+		err = convList(store)
+	case "view":
+		err = convView(store, rest)
+	case "reply":
+		err = convReply(store, rest)
+	case "rm":
+		err = convRemove(store, rest)
+	case "branch":
+		err = convBranch(store, rest)
+	default:
+		fmt.Printf("Unknown conv subcommand %q. Usage: safeguard conv list|view <id>|reply <id> <msg>|rm <id>|branch <id>\n", sub)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func convList(store *conversations.Store) error {
+	convs, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(convs) == 0 {
+		fmt.Println("No saved conversations yet.")
+		return nil
+	}
+
+	selectedID, err := launchConversationBrowser(convs)
+	if err != nil {
+		return err
+	}
+	if selectedID == 0 {
+		return nil
+	}
+	return convView(store, []string{strconv.FormatInt(selectedID, 10)})
+}
+
+func parseConvID(rest []string) (int64, error) {
+	if len(rest) == 0 {
+		return 0, fmt.Errorf("a conversation id is required")
+	}
+	id, err := strconv.ParseInt(rest[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid conversation id %q: %w", rest[0], err)
+	}
+	return id, nil
+}
+
+func convView(store *conversations.Store, rest []string) error {
+	id, err := parseConvID(rest)
+	if err != nil {
+		return err
+	}
+
+	conv, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	messages, err := store.Messages(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Conversation #%d (%s/%s, files: %s)\n", conv.ID, conv.Provider, conv.Model, strings.Join(conv.FilePaths, ", "))
+	if conv.ParentID != nil {
+		fmt.Printf("Branched from #%d\n", *conv.ParentID)
+	}
+	fmt.Println(strings.Repeat("-", 40))
+	for _, m := range messages {
+		fmt.Printf("[%s] %s\n\n", m.Role, m.Content)
+	}
+	return nil
+}
+
+func convReply(store *conversations.Store, rest []string) error {
+	id, err := parseConvID(rest)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 2 {
+		return fmt.Errorf("a follow-up message is required")
+	}
+	reply := strings.Join(rest[1:], " ")
+
+	conv, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	messages, err := store.Messages(id)
+	if err != nil {
+		return err
+	}
+
+	fileCfg, err := llm.LoadFileConfig()
+	if err != nil {
+		return err
+	}
+	backend, err := llm.Get(conv.Provider, llm.Resolve(fileCfg, conv.Provider, conv.Model, apiKeyFromEnv(conv.Provider)))
+	if err != nil {
+		return err
+	}
+
+	var history strings.Builder
+	for _, m := range messages {
+		fmt.Fprintf(&history, "[%s]\n%s\n\n", m.Role, m.Content)
+	}
+	fmt.Fprintf(&history, "[user]\n%s\n", reply)
+
+	response, err := backend.Analyze(context.Background(), history.String())
+	if err != nil {
+		return err
+	}
+
+	if err := store.AddMessage(id, "user", reply); err != nil {
+		return err
+	}
+	if err := store.AddMessage(id, "assistant", response); err != nil {
+		return err
+	}
+
+	fmt.Println(response)
+	return nil
+}
+
+func convRemove(store *conversations.Store, rest []string) error {
+	id, err := parseConvID(rest)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(id); err != nil {
+		return err
+	}
+	fmt.Printf("Removed conversation #%d\n", id)
+	return nil
+}
+
+func convBranch(store *conversations.Store, rest []string) error {
+	id, err := parseConvID(rest)
+	if err != nil {
+		return err
+	}
+	branch, err := store.Branch(id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created branch #%d from #%d\n", branch.ID, id)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crisecheverria/safeguard/llm"
+	"github.com/crisecheverria/safeguard/pkg/report"
+	"github.com/crisecheverria/safeguard/pkg/vcs"
+)
+
+const (
+	findingsToolName        = "report_findings"
+	findingsToolDescription = "Report the deduplicated list of real issues found across the reviewed files."
+
+	summarizerSystemPrompt = "You are an expert code reviewer producing a final, deduplicated list of real issues for automated tooling."
+)
+
+// findingsSchema is the JSON schema summarize forces the model to fill in
+// for any non-"text" format, so the response is guaranteed-parseable
+// structured output rather than prose the model might ignore or wrap in a
+// code fence. A finding's location is a hunk_index, not a line number:
+// formatAnalysis cross-checks it against the diff's real hunks, since
+// models are unreliable at counting lines but reliable at pointing out
+// which changed region a finding is about.
+var findingsSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"findings": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"rule_id": map[string]interface{}{
+						"type":        "string",
+						"description": `Short stable identifier for the issue, e.g. "race-condition"`,
+					},
+					"level": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"error", "warning", "note"},
+					},
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "Human-readable description of the issue",
+					},
+					"file": map[string]interface{}{
+						"type":        "string",
+						"description": "Path of the affected file, matching one of the analyzed files",
+					},
+					"hunk_index": map[string]interface{}{
+						"type":        "integer",
+						"description": "0-based index into that file's diff hunks identifying which changed region this finding is about",
+					},
+				},
+				"required": []string{"rule_id", "level", "message", "file", "hunk_index"},
+			},
+		},
+	},
+	"required": []string{"findings"},
+}
+
+// summarize produces the final analysis text for prompt: the usual
+// streaming/interactive text completion for "text" output, or a forced
+// structured-output call for every other format, since those need a
+// guaranteed-parseable response rather than free-form prose.
+func summarize(ctx context.Context, cfg Config, backend llm.Backend, prompt string) (string, error) {
+	if cfg.Format == "" || cfg.Format == "text" {
+		return runAnalysis(ctx, cfg, backend, prompt, nil)
+	}
+
+	structBackend, ok := backend.(llm.StructuredBackend)
+	if !ok {
+		return "", fmt.Errorf("backend %q does not support the structured output --format %s requires", backend.Name(), cfg.Format)
+	}
+	return structBackend.AnalyzeStructured(ctx, summarizerSystemPrompt, prompt, findingsToolName, findingsToolDescription, findingsSchema)
+}
+
+// formatAnalysis renders the model's response in cfg.Format. For "text" it
+// passes the response through unchanged; every other format expects
+// analysis to be the structured response summarize requested, parses it
+// into raw findings, and resolves each one's location against hunksByFile
+// before rendering.
+func formatAnalysis(cfg Config, analysis string, hunksByFile map[string][]vcs.Hunk) (string, error) {
+	if cfg.Format == "" || cfg.Format == "text" {
+		return analysis, nil
+	}
+
+	raw, err := report.ParseRawFindings(analysis)
+	if err != nil {
+		return "", fmt.Errorf("parsing structured findings: %w", err)
+	}
+	findings := report.ResolveFindings(raw, hunksByFile)
+
+	switch cfg.Format {
+	case "json":
+		data, err := report.ToJSON(findings)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "sarif":
+		data, err := report.ToSARIF(findings)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "markdown":
+		return report.ToMarkdown(findings), nil
+	default:
+		return "", fmt.Errorf("unknown format %q", cfg.Format)
+	}
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/crisecheverria/safeguard/llm"
+	"github.com/crisecheverria/safeguard/pkg/conversations"
+	"github.com/crisecheverria/safeguard/pkg/vcs"
 )
 
 // Model for file selection
@@ -61,9 +68,9 @@ func (i fileItem) Description() string {
 func (i fileItem) FilterValue() string { return i.path }
 
 // File selector initialization
-func launchFileSelector() ([]string, error) {
-	// Get list of git files
-	files, err := listGitFiles()
+func launchFileSelector(repo vcs.Repo) ([]string, error) {
+	// Get list of tracked files
+	files, err := repo.ListFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -121,18 +128,6 @@ func launchFileSelector() ([]string, error) {
 	return finalModel.selectedFiles, nil
 }
 
-// List all files tracked by git
-func listGitFiles() ([]string, error) {
-	cmd := exec.Command("git", "ls-files")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list git files: %w", err)
-	}
-
-	files := strings.Split(string(output), "\n")
-	return files, nil
-}
-
 // BubbleTea Model implementation
 func (m fileModel) Init() tea.Cmd {
 	return tea.EnterAltScreen
@@ -210,4 +205,245 @@ func (m fileModel) View() string {
 		Bold(true)
 
 	return m.list.View() + "\n" + selectedStyle.Render(selectedInfo)
+}
+
+// analysisModel replaces the file selector once the user has confirmed their
+// selection and a diff has been sent to the LLM, rendering the response
+// token-by-token in a scrollable viewport instead of leaving the terminal
+// blank for the duration of the request.
+type analysisModel struct {
+	viewport viewport.Model
+	spinner  spinner.Model
+	content  strings.Builder
+	tokens   <-chan string
+	errCh    <-chan error
+
+	start      time.Time
+	tokenCount int
+	done       bool
+	err        error
+}
+
+type analysisTokenMsg struct {
+	token string
+	ok    bool
+}
+
+type analysisDoneMsg struct{ err error }
+
+// runAnalysisModel drives streamer against prompt inside a Bubble Tea
+// program, returning the fully assembled analysis once streaming completes.
+func runAnalysisModel(ctx context.Context, streamer llm.StreamingBackend, prompt string) (string, error) {
+	tokens := make(chan string)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- streamer.AnalyzeStream(ctx, prompt, tokens)
+	}()
+
+	vp := viewport.New(80, 20)
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	m := analysisModel{
+		viewport: vp,
+		spinner:  sp,
+		tokens:   tokens,
+		errCh:    errCh,
+		start:    time.Now(),
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	result, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+
+	final := result.(analysisModel)
+	if final.err != nil {
+		return "", final.err
+	}
+	if !final.done {
+		return "", fmt.Errorf("analysis cancelled before the response completed")
+	}
+	return final.content.String(), nil
+}
+
+func waitForAnalysisToken(tokens <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		token, ok := <-tokens
+		return analysisTokenMsg{token: token, ok: ok}
+	}
+}
+
+func waitForAnalysisDone(errCh <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		return analysisDoneMsg{err: <-errCh}
+	}
+}
+
+func (m analysisModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, waitForAnalysisToken(m.tokens))
+}
+
+func (m analysisModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height - 4
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+
+	case analysisTokenMsg:
+		if !msg.ok {
+			// Channel closed; the goroutine feeding it has finished, wait
+			// for its final error (or nil) to arrive on errCh.
+			return m, waitForAnalysisDone(m.errCh)
+		}
+		m.content.WriteString(msg.token)
+		m.tokenCount++
+		m.viewport.SetContent(m.content.String())
+		m.viewport.GotoBottom()
+		return m, waitForAnalysisToken(m.tokens)
+
+	case analysisDoneMsg:
+		m.done = true
+		m.err = msg.err
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.done {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m analysisModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#0000FF")).
+		Bold(true).
+		Padding(0, 1)
+
+	statsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
+
+	var status string
+	if m.done {
+		if m.err != nil {
+			status = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Render(fmt.Sprintf("Error: %v", m.err))
+		} else {
+			status = "Done. Press q to exit."
+		}
+	} else {
+		elapsed := time.Since(m.start).Seconds()
+		tokPerSec := float64(m.tokenCount) / elapsed
+		status = fmt.Sprintf("%s Analyzing... %d tokens, %.1f tok/s", m.spinner.View(), m.tokenCount, tokPerSec)
+	}
+
+	return titleStyle.Render("Analysis") + "\n" +
+		m.viewport.View() + "\n" +
+		statsStyle.Render(status)
+}
+
+// conversationItem adapts a saved conversation to list.Item, mirroring
+// fileItem above.
+type conversationItem struct {
+	conversations.Conversation
+}
+
+func (i conversationItem) Title() string {
+	return fmt.Sprintf("#%d  %s/%s  %s", i.ID, i.Provider, i.Model, strings.Join(i.FilePaths, ", "))
+}
+
+func (i conversationItem) Description() string {
+	return i.CreatedAt.Format("2006-01-02 15:04:05")
+}
+
+func (i conversationItem) FilterValue() string {
+	return strings.Join(i.FilePaths, " ")
+}
+
+// conversationListModel lists saved conversations with the same filtering
+// UX as fileModel, so browsing history interactively feels familiar.
+type conversationListModel struct {
+	list       list.Model
+	selectedID int64
+	quitting   bool
+}
+
+// launchConversationBrowser shows convs in a filterable list and returns the
+// id the user picked, or 0 if they quit without choosing one.
+func launchConversationBrowser(convs []conversations.Conversation) (int64, error) {
+	items := make([]list.Item, len(convs))
+	for i, c := range convs {
+		items[i] = conversationItem{c}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#0000FF"))
+	delegate.Styles.SelectedDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#CCCCCC"))
+
+	l := list.New(items, delegate, 80, 20)
+	l.Title = "Saved conversations (/ to filter, Enter to view)"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#0000FF")).Bold(true).Padding(0, 1)
+	l.SetShowHelp(true)
+
+	p := tea.NewProgram(conversationListModel{list: l}, tea.WithAltScreen())
+	m, err := p.Run()
+	if err != nil {
+		return 0, err
+	}
+
+	final := m.(conversationListModel)
+	return final.selectedID, nil
+}
+
+func (m conversationListModel) Init() tea.Cmd {
+	return tea.EnterAltScreen
+}
+
+func (m conversationListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 2)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.quit):
+			m.quitting = true
+			return m, tea.Quit
+		case key.Matches(msg, keys.select_):
+			if item, ok := m.list.SelectedItem().(conversationItem); ok {
+				m.selectedID = item.ID
+			}
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m conversationListModel) View() string {
+	if m.quitting {
+		return "Bye!\n"
+	}
+	return m.list.View()
 }
\ No newline at end of file
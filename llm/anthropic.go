@@ -0,0 +1,372 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/crisecheverria/safeguard/pkg/tools"
+)
+
+func init() {
+	Register("anthropic", newAnthropicBackend)
+}
+
+const anthropicSystemPrompt = "You are an expert at identifying potential bugs in code changes. Be concise and focus only on likely issues."
+
+// anthropicBackend talks to the Anthropic Messages API.
+type anthropicBackend struct {
+	apiKey string
+	model  string
+}
+
+func newAnthropicBackend(cfg BackendConfig) (Backend, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("anthropic: API key is required")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "claude-3-5-sonnet-20240620"
+	}
+	return &anthropicBackend{apiKey: cfg.APIKey, model: model}, nil
+}
+
+func (b *anthropicBackend) Name() string            { return "anthropic" }
+func (b *anthropicBackend) SupportsStreaming() bool { return true }
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system"`
+	Messages  []anthropicMessage `json:"messages"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Role    string `json:"role"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Model        string `json:"model"`
+	StopReason   string `json:"stop_reason"`
+	StopSequence string `json:"stop_sequence"`
+}
+
+func (b *anthropicBackend) Analyze(ctx context.Context, prompt string) (string, error) {
+	reqBody := anthropicRequest{
+		Model:     b.model,
+		MaxTokens: 1024,
+		System:    anthropicSystemPrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", b.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result anthropicResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w - response body: %s", err, string(bodyBytes))
+	}
+
+	if len(result.Content) == 0 {
+		return "", fmt.Errorf("empty response from Anthropic")
+	}
+
+	return result.Content[0].Text, nil
+}
+
+// anthropicStreamEvent covers the fields we care about across the handful of
+// SSE event types the Messages streaming API emits (message_start,
+// content_block_delta, message_stop, ...). Unrecognized types are ignored.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (b *anthropicBackend) AnalyzeStream(ctx context.Context, prompt string, tokens chan<- string) error {
+	defer close(tokens)
+
+	reqBody := anthropicRequest{
+		Model:     b.model,
+		MaxTokens: 1024,
+		System:    anthropicSystemPrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+	reqBody.Stream = true
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", b.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue
+		}
+
+		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" {
+			select {
+			case tokens <- event.Delta.Text:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return nil
+}
+
+// anthropicContentBlock covers every block shape the Messages API sends or
+// accepts once tool use is in play: plain text, tool_use (the model's call),
+// and tool_result (our reply).
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+
+	// text blocks
+	Text string `json:"text,omitempty"`
+
+	// tool_use blocks (model -> us)
+	ID    string                 `json:"id,omitempty"`
+	Name  string                 `json:"name,omitempty"`
+	Input map[string]interface{} `json:"input,omitempty"`
+
+	// tool_result blocks (us -> model)
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+type anthropicToolMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicToolRequest struct {
+	Model      string                 `json:"model"`
+	MaxTokens  int                    `json:"max_tokens"`
+	System     string                 `json:"system"`
+	Messages   []anthropicToolMessage `json:"messages"`
+	Tools      []anthropicTool        `json:"tools,omitempty"`
+	ToolChoice map[string]interface{} `json:"tool_choice,omitempty"`
+}
+
+type anthropicToolResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+}
+
+func (b *anthropicBackend) toolDefinitions(toolset []tools.Tool) []anthropicTool {
+	defs := make([]anthropicTool, len(toolset))
+	for i, t := range toolset {
+		defs[i] = anthropicTool{
+			Name:        t.Name(),
+			Description: t.Description(),
+			InputSchema: t.Parameters(),
+		}
+	}
+	return defs
+}
+
+func (b *anthropicBackend) sendToolRequest(ctx context.Context, systemPrompt string, messages []anthropicToolMessage, toolDefs []anthropicTool, toolChoice map[string]interface{}) (anthropicToolResponse, error) {
+	reqBody := anthropicToolRequest{
+		Model:      b.model,
+		MaxTokens:  1024,
+		System:     systemPrompt,
+		Messages:   messages,
+		Tools:      toolDefs,
+		ToolChoice: toolChoice,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return anthropicToolResponse{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return anthropicToolResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", b.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return anthropicToolResponse{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return anthropicToolResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return anthropicToolResponse{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result anthropicToolResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return anthropicToolResponse{}, fmt.Errorf("failed to decode response: %w - response body: %s", err, string(bodyBytes))
+	}
+	return result, nil
+}
+
+func (b *anthropicBackend) AnalyzeWithTools(ctx context.Context, systemPrompt, prompt string, toolset []tools.Tool) (string, error) {
+	messages := []anthropicToolMessage{
+		{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: prompt}}},
+	}
+
+	toolDefs := b.toolDefinitions(toolset)
+	for i := 0; i < maxToolIterations; i++ {
+		resp, err := b.sendToolRequest(ctx, systemPrompt, messages, toolDefs, nil)
+		if err != nil {
+			return "", err
+		}
+
+		var text strings.Builder
+		var toolUses []anthropicContentBlock
+		for _, block := range resp.Content {
+			switch block.Type {
+			case "text":
+				text.WriteString(block.Text)
+			case "tool_use":
+				toolUses = append(toolUses, block)
+			}
+		}
+
+		if len(toolUses) == 0 {
+			return text.String(), nil
+		}
+
+		messages = append(messages, anthropicToolMessage{Role: "assistant", Content: resp.Content})
+
+		var results []anthropicContentBlock
+		for _, call := range toolUses {
+			result, isErr := invokeTool(ctx, toolset, call.Name, call.Input)
+			results = append(results, anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: call.ID,
+				Content:   result,
+				IsError:   isErr,
+			})
+		}
+		messages = append(messages, anthropicToolMessage{Role: "user", Content: results})
+	}
+
+	return "", fmt.Errorf("anthropic: tool loop exceeded %d iterations without a final answer", maxToolIterations)
+}
+
+// AnalyzeStructured forces the model to answer by calling a single tool
+// named schemaName whose input must match schema, via Anthropic's
+// tool_choice, and returns that input as raw JSON. Unlike prompting the
+// model to "respond with only JSON matching this schema", a forced tool
+// call can't come back prefixed with prose or wrapped in a code fence.
+func (b *anthropicBackend) AnalyzeStructured(ctx context.Context, systemPrompt, prompt, schemaName, schemaDescription string, schema map[string]interface{}) (string, error) {
+	messages := []anthropicToolMessage{
+		{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: prompt}}},
+	}
+	toolDefs := []anthropicTool{{Name: schemaName, Description: schemaDescription, InputSchema: schema}}
+	toolChoice := map[string]interface{}{"type": "tool", "name": schemaName}
+
+	resp, err := b.sendToolRequest(ctx, systemPrompt, messages, toolDefs, toolChoice)
+	if err != nil {
+		return "", err
+	}
+
+	for _, block := range resp.Content {
+		if block.Type == "tool_use" && block.Name == schemaName {
+			data, err := json.Marshal(block.Input)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal structured response: %w", err)
+			}
+			return string(data), nil
+		}
+	}
+
+	return "", fmt.Errorf("anthropic: model did not call the forced %q tool", schemaName)
+}
@@ -0,0 +1,72 @@
+// Package llm defines the pluggable LLM backend abstraction used by safeguard
+// to send diffs/prompts to a provider and get back an analysis.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Backend is implemented by every LLM provider safeguard can talk to.
+type Backend interface {
+	// Analyze sends prompt to the backend and returns the raw text response.
+	Analyze(ctx context.Context, prompt string) (string, error)
+	// Name returns the registered name of the backend (e.g. "anthropic").
+	Name() string
+	// SupportsStreaming reports whether Analyze's output can be streamed
+	// token-by-token via a StreamingBackend.
+	SupportsStreaming() bool
+}
+
+// Factory builds a Backend from a resolved BackendConfig.
+type Factory func(cfg BackendConfig) (Backend, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a backend factory under name. It is meant to be called from
+// an init() function by each backend implementation.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("llm: backend %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// Get builds the backend registered under name using cfg. If name isn't
+// registered, the error lists the backends that are available.
+func Get(name string, cfg BackendConfig) (Backend, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		names := Names()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown provider %q, no backends registered", name)
+		}
+		return nil, fmt.Errorf("unknown provider %q, registered backends: %s", name, strings.Join(names, ", "))
+	}
+	return factory(cfg)
+}
+
+// Names returns the sorted list of currently registered backend names.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
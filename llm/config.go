@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BackendConfig holds everything a Factory needs to construct a Backend.
+type BackendConfig struct {
+	Endpoint string   `yaml:"endpoint"`
+	APIKey   string   `yaml:"apiKey"`
+	Model    string   `yaml:"model"`
+	Models   []string `yaml:"models"`
+}
+
+// FileConfig is the shape of ~/.config/safeguard/config.yaml.
+type FileConfig struct {
+	DefaultProvider string                   `yaml:"defaultProvider"`
+	Backends        map[string]BackendConfig `yaml:"backends"`
+}
+
+// ConfigPath returns the path safeguard reads its backend config from,
+// expanding to the user's home directory.
+func ConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "safeguard", "config.yaml"), nil
+}
+
+// LoadFileConfig reads and parses the backend config file. A missing file is
+// not an error; it returns a zero-value FileConfig so callers can still fall
+// back to flags and environment variables.
+func LoadFileConfig() (FileConfig, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return FileConfig{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FileConfig{}, nil
+		}
+		return FileConfig{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg FileConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return FileConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Resolve merges the config file's settings for provider with any
+// explicitly-provided override values (flags take precedence over the file).
+func Resolve(fileCfg FileConfig, provider, model, apiKey string) BackendConfig {
+	cfg := fileCfg.Backends[provider]
+
+	if model != "" {
+		cfg.Model = model
+	}
+	if apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+	return cfg
+}
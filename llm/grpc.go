@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func init() {
+	Register("grpc", newGRPCBackend)
+}
+
+// grpcPredictMethod is the fully-qualified RPC invoked on the target
+// endpoint. It's named and shaped after LocalAI's backend.proto "Predict"
+// call, but sends/receives a generic google.protobuf.Struct rather than
+// LocalAI's own PredictOptions/Reply messages, so it does not interoperate
+// with an unmodified LocalAI server out of the box — only with a server
+// that implements this same Struct-based convention at that method name.
+const grpcPredictMethod = "/backend.Backend/Predict"
+
+// grpcBackend is a generic client for a self-hosted inference server that
+// implements grpcPredictMethod's Struct-based request/response convention,
+// for local models that don't have a dedicated implementation here.
+type grpcBackend struct {
+	endpoint string
+	model    string
+}
+
+func newGRPCBackend(cfg BackendConfig) (Backend, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("grpc: endpoint is required, e.g. localhost:50051")
+	}
+	return &grpcBackend{endpoint: cfg.Endpoint, model: cfg.Model}, nil
+}
+
+func (b *grpcBackend) Name() string            { return "grpc" }
+func (b *grpcBackend) SupportsStreaming() bool { return false }
+
+func (b *grpcBackend) Analyze(ctx context.Context, prompt string) (string, error) {
+	conn, err := grpc.NewClient(b.endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return "", fmt.Errorf("failed to dial grpc backend at %s: %w", b.endpoint, err)
+	}
+	defer conn.Close()
+
+	req, err := structpb.NewStruct(map[string]interface{}{
+		"model":  b.model,
+		"prompt": prompt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build grpc request: %w", err)
+	}
+
+	resp := &structpb.Struct{}
+	if err := conn.Invoke(ctx, grpcPredictMethod, req, resp); err != nil {
+		return "", fmt.Errorf("grpc predict call failed: %w", err)
+	}
+
+	result, ok := resp.Fields["result"]
+	if !ok {
+		return "", fmt.Errorf("grpc backend response missing %q field", "result")
+	}
+
+	return result.GetStringValue(), nil
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func init() {
+	Register("ollama", newOllamaBackend)
+}
+
+// ollamaBackend talks to a local Ollama server's /api/chat endpoint, so
+// users running llama.cpp/Ollama models locally get the same interface as
+// the hosted providers.
+type ollamaBackend struct {
+	endpoint string
+	model    string
+}
+
+func newOllamaBackend(cfg BackendConfig) (Backend, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+	model := cfg.Model
+	if model == "" {
+		return nil, fmt.Errorf("ollama: model is required, e.g. --model=qwen2.5-coder")
+	}
+	return &ollamaBackend{endpoint: endpoint, model: model}, nil
+}
+
+func (b *ollamaBackend) Name() string            { return "ollama" }
+func (b *ollamaBackend) SupportsStreaming() bool { return false }
+
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message ollamaChatMessage `json:"message"`
+	Done    bool              `json:"done"`
+}
+
+func (b *ollamaBackend) Analyze(ctx context.Context, prompt string) (string, error) {
+	reqBody := ollamaChatRequest{
+		Model: b.model,
+		Messages: []ollamaChatMessage{
+			{Role: "system", Content: anthropicSystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Stream: false,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.endpoint+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach ollama at %s: %w", b.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("ollama request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result ollamaChatResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w - response body: %s", err, string(bodyBytes))
+	}
+
+	if result.Message.Content == "" {
+		return "", fmt.Errorf("empty response from ollama")
+	}
+
+	return result.Message.Content, nil
+}
@@ -0,0 +1,210 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/crisecheverria/safeguard/pkg/tools"
+)
+
+func init() {
+	Register("openai", newOpenAIBackend)
+}
+
+const openAISystemPrompt = "You are an expert at identifying potential bugs in code changes. Be concise and focus only on likely issues."
+
+// openAIBackend talks to the OpenAI chat completions API.
+type openAIBackend struct {
+	client *openai.Client
+	model  string
+}
+
+func newOpenAIBackend(cfg BackendConfig) (Backend, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("openai: API key is required")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "gpt-4-turbo"
+	}
+	return &openAIBackend{client: openai.NewClient(cfg.APIKey), model: model}, nil
+}
+
+func (b *openAIBackend) Name() string            { return "openai" }
+func (b *openAIBackend) SupportsStreaming() bool { return true }
+
+func (b *openAIBackend) Analyze(ctx context.Context, prompt string) (string, error) {
+	resp, err := b.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: b.model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: openAISystemPrompt},
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			MaxTokens: 1024,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get OpenAI analysis: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from OpenAI")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (b *openAIBackend) AnalyzeStream(ctx context.Context, prompt string, tokens chan<- string) error {
+	defer close(tokens)
+
+	stream, err := b.client.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: b.model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: openAISystemPrompt},
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			MaxTokens: 1024,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start OpenAI stream: %w", err)
+	}
+	defer stream.Close()
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read OpenAI stream: %w", err)
+		}
+
+		if len(resp.Choices) == 0 {
+			continue
+		}
+
+		if delta := resp.Choices[0].Delta.Content; delta != "" {
+			select {
+			case tokens <- delta:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (b *openAIBackend) toolDefinitions(toolset []tools.Tool) []openai.Tool {
+	defs := make([]openai.Tool, len(toolset))
+	for i, t := range toolset {
+		defs[i] = openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name(),
+				Description: t.Description(),
+				Parameters:  t.Parameters(),
+			},
+		}
+	}
+	return defs
+}
+
+func (b *openAIBackend) AnalyzeWithTools(ctx context.Context, systemPrompt, prompt string, toolset []tools.Tool) (string, error) {
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: prompt},
+	}
+
+	for i := 0; i < maxToolIterations; i++ {
+		resp, err := b.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model:     b.model,
+			Messages:  messages,
+			Tools:     b.toolDefinitions(toolset),
+			MaxTokens: 1024,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get OpenAI analysis: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("empty response from OpenAI")
+		}
+
+		msg := resp.Choices[0].Message
+		if len(msg.ToolCalls) == 0 {
+			return msg.Content, nil
+		}
+
+		messages = append(messages, msg)
+		for _, call := range msg.ToolCalls {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				messages = append(messages, openai.ChatCompletionMessage{
+					Role:       openai.ChatMessageRoleTool,
+					ToolCallID: call.ID,
+					Content:    fmt.Sprintf("invalid arguments: %v", err),
+				})
+				continue
+			}
+
+			result, _ := invokeTool(ctx, toolset, call.Function.Name, args)
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				ToolCallID: call.ID,
+				Content:    result,
+			})
+		}
+	}
+
+	return "", fmt.Errorf("openai: tool loop exceeded %d iterations without a final answer", maxToolIterations)
+}
+
+// AnalyzeStructured forces the model to answer by calling a single function
+// named schemaName whose arguments must match schema, via OpenAI's
+// ToolChoice, and returns those arguments as raw JSON. Unlike prompting the
+// model to "respond with only JSON matching this schema", a forced function
+// call can't come back prefixed with prose or wrapped in a code fence.
+func (b *openAIBackend) AnalyzeStructured(ctx context.Context, systemPrompt, prompt, schemaName, schemaDescription string, schema map[string]interface{}) (string, error) {
+	resp, err := b.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: b.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		Tools: []openai.Tool{{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        schemaName,
+				Description: schemaDescription,
+				Parameters:  schema,
+			},
+		}},
+		ToolChoice: openai.ToolChoice{
+			Type:     openai.ToolTypeFunction,
+			Function: openai.ToolFunction{Name: schemaName},
+		},
+		MaxTokens: 1024,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get OpenAI structured analysis: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from OpenAI")
+	}
+
+	for _, call := range resp.Choices[0].Message.ToolCalls {
+		if call.Function.Name == schemaName {
+			return call.Function.Arguments, nil
+		}
+	}
+
+	return "", fmt.Errorf("openai: model did not call the forced %q tool", schemaName)
+}
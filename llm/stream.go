@@ -0,0 +1,14 @@
+package llm
+
+import "context"
+
+// StreamingBackend is implemented by backends that can deliver their
+// response incrementally instead of all at once. Callers type-assert
+// Backend to StreamingBackend and fall back to Analyze when unsupported.
+type StreamingBackend interface {
+	Backend
+	// AnalyzeStream behaves like Analyze but writes each token to tokens as
+	// it arrives instead of returning the full text. tokens is closed by
+	// the implementation once the response is complete or an error occurs.
+	AnalyzeStream(ctx context.Context, prompt string, tokens chan<- string) error
+}
@@ -0,0 +1,15 @@
+package llm
+
+import "context"
+
+// StructuredBackend is implemented by backends that can force a model to
+// return a single JSON object matching schema via tool-forcing (Anthropic's
+// tool_choice, OpenAI's ToolChoice), instead of relying on prose
+// instructions a model might ignore, reformat, or wrap in a code fence.
+type StructuredBackend interface {
+	Backend
+	// AnalyzeStructured sends prompt to the backend, forcing it to respond
+	// by calling a single tool named schemaName whose input must match
+	// schema, and returns that input as raw JSON.
+	AnalyzeStructured(ctx context.Context, systemPrompt, prompt, schemaName, schemaDescription string, schema map[string]interface{}) (string, error)
+}
@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crisecheverria/safeguard/pkg/tools"
+)
+
+// maxToolIterations bounds the tool-call loop so a model that keeps calling
+// tools without ever answering can't run forever.
+const maxToolIterations = 8
+
+// ToolCallingBackend is implemented by backends that can drive a tool-call
+// loop: send the model a prompt plus a toolset, execute whatever tools it
+// asks for, and feed the results back until it produces a final answer.
+type ToolCallingBackend interface {
+	Backend
+	AnalyzeWithTools(ctx context.Context, systemPrompt, prompt string, toolset []tools.Tool) (string, error)
+}
+
+func findTool(toolset []tools.Tool, name string) (tools.Tool, bool) {
+	for _, t := range toolset {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// invokeTool runs the named tool with args and reports whether the result
+// represents an error, so callers can set the provider-specific "this tool
+// call failed" flag alongside the text they feed back to the model.
+func invokeTool(ctx context.Context, toolset []tools.Tool, name string, args map[string]interface{}) (result string, isError bool) {
+	tool, ok := findTool(toolset, name)
+	if !ok {
+		return fmt.Sprintf("tool %q is not available to this agent", name), true
+	}
+
+	output, err := tool.Execute(ctx, args)
+	if err != nil {
+		return err.Error(), true
+	}
+	return output, false
+}
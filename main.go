@@ -1,18 +1,20 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"os/exec"
+	"runtime"
 	"strings"
+	"time"
 
-	"github.com/sashabaranov/go-openai"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/crisecheverria/safeguard/llm"
+	"github.com/crisecheverria/safeguard/pkg/agents"
+	"github.com/crisecheverria/safeguard/pkg/conversations"
+	"github.com/crisecheverria/safeguard/pkg/vcs"
 )
 
 type Config struct {
@@ -22,91 +24,172 @@ type Config struct {
 	Model        string
 	Provider     string
 	APIKey       string
+	FileConfig   llm.FileConfig
+	Interactive  bool
+	Stream       bool
+	Agent        string
+	Concurrency  int
+	Format       string
 }
 
 func main() {
 	// Display the CLI version
 	fmt.Println("Safeguard - Code Change Analysis Tool v1.0.0")
 	fmt.Println("Â© 2025 - Licensed under MIT License - See LICENSE file for details")
-	cfg := parseFlags()
+
+	if len(os.Args) > 1 && os.Args[1] == "conv" {
+		runConvCommand(os.Args[2:])
+		return
+	}
+
+	repo, err := vcs.Open(".")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := parseFlags(repo)
 
 	if cfg.SourceBranch == "" || cfg.TargetBranch == "" {
 		fmt.Println("Error: Source branch and target branch are required")
 		os.Exit(1)
 	}
-	
+
 	if len(cfg.FilePaths) == 0 {
 		fmt.Println("Error: At least one file path is required. Use --interactive flag to select files interactively.")
 		os.Exit(1)
 	}
 
-	// Process multiple files
-	var allDiffs []string
-	var allFilePaths []string
-	
-	for _, filePath := range cfg.FilePaths {
-		fmt.Printf("\nProcessing file: %s\n", filePath)
-		
-		sourceContent, err := getFileFromBranch(filePath, cfg.SourceBranch)
-		if err != nil {
-			fmt.Printf("Error getting file %s from source branch: %v\n", filePath, err)
-			continue // Skip this file and continue with others
-		}
+	backend, err := llm.Get(cfg.Provider, llm.Resolve(cfg.FileConfig, cfg.Provider, cfg.Model, cfg.APIKey))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 
-		targetContent, err := getFileFromBranch(filePath, cfg.TargetBranch)
+	var agent *agents.Agent
+	if cfg.Agent != "" {
+		repoRoot, err := os.Getwd()
 		if err != nil {
-			fmt.Printf("Error getting file %s from target branch: %v\n", filePath, err)
-			continue // Skip this file and continue with others
+			fmt.Printf("Error resolving repository root: %v\n", err)
+			os.Exit(1)
 		}
-
-		diff, err := generateDiff(sourceContent, targetContent, cfg.SourceBranch, cfg.TargetBranch, filePath)
+		agent, err = agents.New(cfg.Agent, repoRoot)
 		if err != nil {
-			fmt.Printf("Error generating diff for %s: %v\n", filePath, err)
-			continue // Skip this file and continue with others
-		}
-		
-		if strings.TrimSpace(diff) != "" {
-			allDiffs = append(allDiffs, fmt.Sprintf("=== File: %s ===\n%s", filePath, diff))
-			allFilePaths = append(allFilePaths, filePath)
-		} else {
-			fmt.Printf("No changes detected in %s\n", filePath)
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Printf("Using agent persona: %s\n", agent.Name)
+	}
+
+	ctx := context.Background()
+
+	fmt.Printf("\nAnalyzing %d file(s) with concurrency %d...\n", len(cfg.FilePaths), cfg.Concurrency)
+	combinedDiff, prompt, hunksByFile, ok, err := runFileAnalysis(ctx, cfg, repo, backend, agent)
+	if err != nil {
+		fmt.Printf("Error analyzing files: %v\n", err)
+		os.Exit(1)
 	}
-	
-	if len(allDiffs) == 0 {
+	if !ok {
 		fmt.Println("No changes detected in any of the selected files.")
 		os.Exit(0)
 	}
-	
-	// Display diff summary for verification
-	fmt.Printf("\nDiffs generated successfully for %d files.\n", len(allDiffs))
-	
-	// Combine all diffs for analysis
-	combinedDiff := strings.Join(allDiffs, "\n\n")
-	prompt := buildPrompt(strings.Join(allFilePaths, ", "), combinedDiff)
-
-	var analysis string
-	var err error
-	switch cfg.Provider {
-	case "anthropic":
-		analysis, err = getAnthropicAnalysis(cfg.APIKey, cfg.Model, prompt)
-	case "openai":
-		analysis, err = getOpenAIAnalysis(cfg.APIKey, cfg.Model, prompt)
-	default:
-		fmt.Println("Error: Unknown provider. Use 'anthropic' or 'openai'")
+
+	// The agent persona (if any) already drove tool-calling analysis over
+	// each file's diff above; this final pass merges those findings into one
+	// report, so it runs as plain summarization rather than another agent
+	// turn.
+	fmt.Printf("\nStarting %s summary...\n", backend.Name())
+	analysis, err := summarize(ctx, cfg, backend, prompt)
+	if err != nil {
+		fmt.Printf("Error getting analysis: %v\n", err)
 		os.Exit(1)
 	}
 
+	output, err := formatAnalysis(cfg, analysis, hunksByFile)
 	if err != nil {
-		fmt.Printf("Error getting analysis: %v\n", err)
+		fmt.Printf("Error formatting analysis as %s: %v\n", cfg.Format, err)
 		os.Exit(1)
 	}
 
 	fmt.Println("\n--- Analysis of potential bugs ---")
-	fmt.Println(analysis)
+	fmt.Println(output)
+
+	saveConversation(cfg, backend.Name(), combinedDiff, prompt, analysis)
+}
+
+// saveConversation persists the session so it can be revisited with
+// `safeguard conv`. Failures are reported but don't fail the run, since the
+// analysis itself already succeeded.
+func saveConversation(cfg Config, provider, diff, prompt, analysis string) {
+	store, err := conversations.Open()
+	if err != nil {
+		fmt.Printf("Warning: failed to open conversation store: %v\n", err)
+		return
+	}
+	defer store.Close()
+
+	conv, err := store.Create(provider, cfg.Model, cfg.FilePaths, diff, prompt, analysis)
+	if err != nil {
+		fmt.Printf("Warning: failed to save conversation: %v\n", err)
+		return
+	}
+	fmt.Printf("\nSaved as conversation #%d. Reply with: safeguard conv reply %d \"...\"\n", conv.ID, conv.ID)
+}
+
+// runAnalysis dispatches to the agent tool-call loop, the streaming path, or
+// the interactive rendering path, whichever best fits cfg and what backend
+// supports, falling back to a single blocking call otherwise.
+func runAnalysis(ctx context.Context, cfg Config, backend llm.Backend, prompt string, agent *agents.Agent) (string, error) {
+	if agent != nil {
+		toolBackend, ok := backend.(llm.ToolCallingBackend)
+		if !ok {
+			return "", fmt.Errorf("backend %q does not support tool-calling agents", backend.Name())
+		}
+		return toolBackend.AnalyzeWithTools(ctx, agent.SystemPrompt, prompt, agent.Tools)
+	}
+
+	streamer, ok := backend.(llm.StreamingBackend)
+	if !ok || !cfg.Stream {
+		return backend.Analyze(ctx, prompt)
+	}
+
+	if cfg.Interactive {
+		return runAnalysisModel(ctx, streamer, prompt)
+	}
+	return streamToStdout(ctx, streamer, prompt)
+}
+
+// streamToStdout prints tokens as they arrive and reports a tokens/sec
+// summary once the stream ends.
+func streamToStdout(ctx context.Context, streamer llm.StreamingBackend, prompt string) (string, error) {
+	tokens := make(chan string)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- streamer.AnalyzeStream(ctx, prompt, tokens)
+	}()
+
+	var full strings.Builder
+	count := 0
+	start := time.Now()
+	for token := range tokens {
+		fmt.Print(token)
+		full.WriteString(token)
+		count++
+	}
+
+	if err := <-errCh; err != nil {
+		return "", err
+	}
+
+	elapsed := time.Since(start).Seconds()
+	statsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
+	fmt.Printf("\n%s\n", statsStyle.Render(fmt.Sprintf("(%d tokens in %.1fs, %.1f tok/s)", count, elapsed, float64(count)/elapsed)))
+
+	return full.String(), nil
 }
 
-func parseFlags() Config {
+func parseFlags(repo vcs.Repo) Config {
 	var cfg Config
 	var interactive bool
 	var filePath string
@@ -114,267 +197,108 @@ func parseFlags() Config {
 	flag.StringVar(&filePath, "file", "", "Path to the file to analyze")
 	flag.StringVar(&cfg.SourceBranch, "source", "", "Source branch")
 	flag.StringVar(&cfg.TargetBranch, "target", "", "Target branch")
-	flag.StringVar(&cfg.Model, "model", "", "Model to use (claude-3-opus-20240229 for Anthropic, gpt-4-turbo for OpenAI)")
-	flag.StringVar(&cfg.Provider, "provider", "anthropic", "LLM provider (anthropic or openai)")
+	flag.StringVar(&cfg.Model, "model", "", "Model to use (claude-3-opus-20240229 for Anthropic, gpt-4-turbo for OpenAI, qwen2.5-coder for Ollama, ...)")
+	flag.StringVar(&cfg.Provider, "provider", "anthropic", "LLM provider, one of: "+strings.Join(llm.Names(), ", ")+" (configured in ~/.config/safeguard/config.yaml)")
 	flag.StringVar(&cfg.APIKey, "key", "", "API key for the provider")
 	flag.BoolVar(&interactive, "interactive", false, "Use interactive mode to select files")
+	flag.BoolVar(&cfg.Stream, "stream", true, "Stream the analysis token-by-token if the backend supports it")
+	flag.StringVar(&cfg.Agent, "a", "", "Agent persona to use for tool-calling analysis (reviewer, security, perf)")
+	flag.StringVar(&cfg.Agent, "agent", "", "Agent persona to use for tool-calling analysis (reviewer, security, perf)")
+	flag.IntVar(&cfg.Concurrency, "concurrency", runtime.NumCPU(), "Number of files to diff and analyze in parallel")
+	flag.StringVar(&cfg.Format, "format", "text", "Output format: text, sarif, json, or markdown")
 
 	flag.Parse()
-	
+
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	switch cfg.Format {
+	case "text", "sarif", "json", "markdown":
+	default:
+		fmt.Printf("Error: unknown --format %q (want text, sarif, json, or markdown)\n", cfg.Format)
+		os.Exit(1)
+	}
+
+	fileCfg, err := llm.LoadFileConfig()
+	if err != nil {
+		fmt.Printf("Error loading ~/.config/safeguard/config.yaml: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.FileConfig = fileCfg
+	if cfg.Provider == "anthropic" && fileCfg.DefaultProvider != "" && !flagWasSet("provider") {
+		cfg.Provider = fileCfg.DefaultProvider
+	}
+
 	// Handle interactive file selection if enabled or no file specified
 	if interactive || filePath == "" {
 		fmt.Println("Launching interactive file selector...")
-		selectedFiles, err := launchFileSelector()
+		selectedFiles, err := launchFileSelector(repo)
 		if err != nil {
 			fmt.Printf("Error in interactive mode: %v\n", err)
 			os.Exit(1)
 		}
 		cfg.FilePaths = selectedFiles
+		cfg.Interactive = true
 		fmt.Printf("Selected files: %v\n", cfg.FilePaths)
 	} else {
 		cfg.FilePaths = []string{filePath}
 	}
 
-	// Set default models if not provided
-	if cfg.Model == "" {
-		if cfg.Provider == "anthropic" {
-			// Use newer models with correct format
-			cfg.Model = "claude-3-5-sonnet-20240620"
-			// Other options:
-			// cfg.Model = "claude-3-haiku-20240307" 
-			// cfg.Model = "claude-3-sonnet-20240229"
-			// cfg.Model = "claude-3-opus-20240229"
-		} else {
-			cfg.Model = "gpt-4-turbo"
-		}
+	// Print the selected model, if one was given; otherwise the backend uses
+	// config.yaml's model for it, then its own default if it has one
+	// (ollama has none and fails without a model).
+	if cfg.Model != "" {
+		fmt.Printf("Using model: %s\n", cfg.Model)
 	}
-	
-	// Print the selected model
-	fmt.Printf("Using model: %s\n", cfg.Model)
-
-	// Check for API key in env var if not provided
-	if cfg.APIKey == "" {
-		switch cfg.Provider {
-		case "anthropic":
-			cfg.APIKey = os.Getenv("ANTHROPIC_API_KEY")
-			if cfg.APIKey == "" {
-				fmt.Println("Error: ANTHROPIC_API_KEY environment variable not set. Use --key flag or set the environment variable.")
-				os.Exit(1)
-			}
-		case "openai":
-			cfg.APIKey = os.Getenv("OPENAI_API_KEY")
-			if cfg.APIKey == "" {
-				fmt.Println("Error: OPENAI_API_KEY environment variable not set. Use --key flag or set the environment variable.")
-				os.Exit(1)
-			}
+
+	// Check for API key in env var if not provided by flag or config.yaml
+	if cfg.APIKey == "" && cfg.FileConfig.Backends[cfg.Provider].APIKey == "" {
+		cfg.APIKey = apiKeyFromEnv(cfg.Provider)
+		if cfg.APIKey == "" && (cfg.Provider == "anthropic" || cfg.Provider == "openai") {
+			fmt.Printf("Error: %s environment variable not set. Use --key flag, config.yaml, or set the environment variable.\n", envVarForProvider(cfg.Provider))
+			os.Exit(1)
 		}
 	}
 
 	return cfg
 }
 
-func getFileFromBranch(filePath, branch string) (string, error) {
-	// Print the branch and file we're fetching for debugging
-	fmt.Printf("Fetching file '%s' from branch '%s'\n", filePath, branch)
-	// Expand home directory if path starts with ~
-	if strings.HasPrefix(filePath, "~") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %w", err)
-		}
-		filePath = strings.Replace(filePath, "~", home, 1)
+// envVarForProvider returns the environment variable safeguard reads an API
+// key from for provider, if that provider has a conventional one.
+func envVarForProvider(provider string) string {
+	switch provider {
+	case "anthropic":
+		return "ANTHROPIC_API_KEY"
+	case "openai":
+		return "OPENAI_API_KEY"
+	default:
+		return ""
 	}
+}
 
-	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", branch, filePath))
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("git show failed: %w - %s", err, string(output))
+// apiKeyFromEnv looks up provider's API key from its conventional
+// environment variable, returning "" if it has none or isn't set.
+func apiKeyFromEnv(provider string) string {
+	if envVar := envVarForProvider(provider); envVar != "" {
+		return os.Getenv(envVar)
 	}
-	return string(output), nil
+	return ""
 }
 
-func generateDiff(sourceContent, targetContent string, sourceBranch, targetBranch, filePath string) (string, error) {
-	// Create temporary files
-	sourceFile, err := os.CreateTemp("", "source-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create source temp file: %w", err)
-	}
-	defer os.Remove(sourceFile.Name())
-	defer sourceFile.Close()
-	
-	targetFile, err := os.CreateTemp("", "target-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create target temp file: %w", err)
-	}
-	defer os.Remove(targetFile.Name())
-	defer targetFile.Close()
-	
-	// Write content to temp files
-	if _, err := sourceFile.WriteString(sourceContent); err != nil {
-		return "", fmt.Errorf("failed to write to source temp file: %w", err)
-	}
-	
-	if _, err := targetFile.WriteString(targetContent); err != nil {
-		return "", fmt.Errorf("failed to write to target temp file: %w", err)
-	}
-	
-	// Close files to ensure content is flushed to disk
-	sourceFile.Close()
-	targetFile.Close()
-	
-	// Run diff on the temp files with labeled headers
-	cmd := exec.Command("diff", "-u", 
-		"--label", fmt.Sprintf("%s:%s", sourceBranch, filePath), 
-		"--label", fmt.Sprintf("%s:%s", targetBranch, filePath), 
-		sourceFile.Name(), targetFile.Name())
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-	
-	if err := cmd.Run(); err != nil {
-		// diff exits with status 1 if there are differences, which is expected
-		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() > 1 {
-			return "", fmt.Errorf("diff command failed: %w", err)
+// flagWasSet reports whether name was explicitly passed on the command line,
+// as opposed to only carrying its zero-value default.
+func flagWasSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
-	}
-	
-	return stdout.String(), nil
+	})
+	return set
 }
 
 func buildPrompt(filePaths string, diff string) string {
 	template := "You are an expert code reviewer specializing in finding bugs. Analyze the following changes in the file(s) %s to identify potential bugs, logic errors, edge cases, and performance issues.\n\nDiff:\n```\n%s\n```\n\nFocus on:\n1. Logic errors\n2. Race conditions\n3. Memory leaks\n4. Security vulnerabilities\n5. API contract violations\n6. Edge cases\n7. Performance issues\n8. Cross-file dependencies and impacts\n\nProvide a concise analysis listing only potential issues. If there are no issues, state that explicitly. When analyzing multiple files, consider how changes might affect interactions between files."
 	return fmt.Sprintf(template, filePaths, diff)
 }
-
-type AnthropicMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type AnthropicRequest struct {
-	Model     string            `json:"model"`
-	MaxTokens int               `json:"max_tokens"`
-	System    string            `json:"system"`
-	Messages  []AnthropicMessage `json:"messages"`
-}
-
-type AnthropicResponse struct {
-	ID       string `json:"id"`
-	Type     string `json:"type"`
-	Role     string `json:"role"`
-	Content  []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
-	Model    string `json:"model"`
-	StopReason string `json:"stop_reason"`
-	StopSequence string `json:"stop_sequence"`
-}
-
-func getAnthropicAnalysis(apiKey, model, prompt string) (string, error) {
-	// Print the starting point for debugging
-	fmt.Println("\nStarting Anthropic API analysis...")
-	if apiKey == "" {
-		return "", fmt.Errorf("Anthropic API key is required")
-	}
-
-	reqBody := AnthropicRequest{
-		Model:     model,
-		MaxTokens: 1024,
-		System:    "You are an expert at identifying potential bugs in code changes. Be concise and focus only on likely issues.",
-		Messages: []AnthropicMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", apiKey)
-	// Use the current API version
-	req.Header.Set("anthropic-version", "2023-06-01")
-	
-	// Print request body for debugging
-	fmt.Printf("\nRequest payload: %s\n", string(jsonData))
-	// Debug API request
-	fmt.Println("\nSending request to Anthropic API...")
-	fmt.Println("Headers:")
-	for k, v := range req.Header {
-		fmt.Printf("  %s: %s\n", k, v)
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		fmt.Printf("API Error: Status %d\nResponse: %s\n", resp.StatusCode, string(bodyBytes))
-		return "", fmt.Errorf("API request failed with status %d", resp.StatusCode)
-	}
-
-	var result AnthropicResponse
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	fmt.Printf("Response body: %s\n", string(bodyBytes))
-	
-	// Reset the response body for JSON decoding
-	resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-	
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w - response body: %s", err, string(bodyBytes))
-	}
-
-	if len(result.Content) == 0 {
-		return "", fmt.Errorf("empty response from Anthropic")
-	}
-
-	return result.Content[0].Text, nil
-}
-
-func getOpenAIAnalysis(apiKey, model, prompt string) (string, error) {
-	if apiKey == "" {
-		return "", fmt.Errorf("OpenAI API key is required")
-	}
-
-	client := openai.NewClient(apiKey)
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "You are an expert at identifying potential bugs in code changes. Be concise and focus only on likely issues.",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			MaxTokens: 1024,
-		},
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("failed to get OpenAI analysis: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("empty response from OpenAI")
-	}
-
-	return resp.Choices[0].Message.Content, nil
-}
\ No newline at end of file
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crisecheverria/safeguard/llm"
+	"github.com/crisecheverria/safeguard/pkg/agents"
+	"github.com/crisecheverria/safeguard/pkg/vcs"
+)
+
+// maxDiffTokens bounds how much of a single file's diff goes into its
+// per-file analysis request, so one oversized file doesn't blow past a
+// provider's context window and silently truncate everything after it.
+const maxDiffTokens = 4000
+
+// fileStatus tracks where a single file is in the analysis pipeline,
+// surfaced to the progress view as it moves through.
+type fileStatus string
+
+const (
+	statusQueued    fileStatus = "queued"
+	statusDiffing   fileStatus = "diffing"
+	statusAnalyzing fileStatus = "analyzing"
+	statusDone      fileStatus = "done"
+	statusSkipped   fileStatus = "skipped"
+	statusError     fileStatus = "error"
+)
+
+// fileProgress is one status update for a file, sent as it moves through
+// analyzeFiles.
+type fileProgress struct {
+	FilePath string
+	Status   fileStatus
+	Err      error
+}
+
+// fileResult is one file's diff, parsed hunks, and per-file analysis, or the
+// error that stopped it; Diff is empty if the file had no changes between
+// revisions. Hunks is the deterministic source of truth for line ranges:
+// the final report resolves a finding's location against it rather than
+// trusting whatever line number the model reports.
+type fileResult struct {
+	FilePath string
+	Diff     string
+	Hunks    []vcs.Hunk
+	Analysis string
+	Err      error
+}
+
+// estimateTokens is a rough chars/4 heuristic; backends don't expose an
+// exact tokenizer, so this only needs to keep requests in the right
+// ballpark relative to a provider's context window.
+func estimateTokens(s string) int {
+	return len(s) / 4
+}
+
+// truncateDiff keeps the first maxTokens worth of diff and appends a marker
+// noting the rest was dropped, rather than silently sending a truncated
+// diff with no indication anything is missing.
+func truncateDiff(diff string, maxTokens int) string {
+	limit := maxTokens * 4
+	if limit >= len(diff) {
+		return diff
+	}
+	return diff[:limit] + "\n... (diff truncated, exceeded per-file token budget)\n"
+}
+
+// analyzeFiles diffs and analyzes each of filePaths concurrently, bounded by
+// cfg.Concurrency, reporting per-file progress on progress as each file
+// advances. A single file's diff or analysis error is recorded on its
+// fileResult rather than aborting the others, matching how the old serial
+// loop skipped a failing file and kept going.
+//
+// When agent is non-nil, each file is analyzed through its tool-calling loop
+// (so the persona can read_file/search_symbol/etc. around the diff) instead
+// of a single plain completion.
+func analyzeFiles(ctx context.Context, cfg Config, repo vcs.Repo, backend llm.Backend, agent *agents.Agent, filePaths []string, progress chan<- fileProgress) ([]fileResult, error) {
+	var toolBackend llm.ToolCallingBackend
+	if agent != nil {
+		var ok bool
+		toolBackend, ok = backend.(llm.ToolCallingBackend)
+		if !ok {
+			return nil, fmt.Errorf("backend %q does not support tool-calling agents", backend.Name())
+		}
+	}
+
+	results := make([]fileResult, len(filePaths))
+	sem := make(chan struct{}, cfg.Concurrency)
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, filePath := range filePaths {
+		i, filePath := i, filePath
+		progress <- fileProgress{FilePath: filePath, Status: statusQueued}
+
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			progress <- fileProgress{FilePath: filePath, Status: statusDiffing}
+			diff, hunks, err := repo.DiffAndHunks(filePath, cfg.SourceBranch, cfg.TargetBranch)
+			if err != nil {
+				results[i] = fileResult{FilePath: filePath, Err: err}
+				progress <- fileProgress{FilePath: filePath, Status: statusError, Err: err}
+				return nil
+			}
+			if strings.TrimSpace(diff) == "" {
+				results[i] = fileResult{FilePath: filePath}
+				progress <- fileProgress{FilePath: filePath, Status: statusSkipped}
+				return nil
+			}
+
+			if estimateTokens(diff) > maxDiffTokens {
+				diff = truncateDiff(diff, maxDiffTokens)
+			}
+
+			progress <- fileProgress{FilePath: filePath, Status: statusAnalyzing}
+			var analysis string
+			if toolBackend != nil {
+				analysis, err = toolBackend.AnalyzeWithTools(gctx, agent.SystemPrompt, buildPrompt(filePath, diff), agent.Tools)
+			} else {
+				analysis, err = backend.Analyze(gctx, buildPrompt(filePath, diff))
+			}
+			if err != nil {
+				results[i] = fileResult{FilePath: filePath, Diff: diff, Hunks: hunks, Err: err}
+				progress <- fileProgress{FilePath: filePath, Status: statusError, Err: err}
+				return nil
+			}
+
+			results[i] = fileResult{FilePath: filePath, Diff: diff, Hunks: hunks, Analysis: analysis}
+			progress <- fileProgress{FilePath: filePath, Status: statusDone}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	close(progress)
+	return results, err
+}
+
+// runFileAnalysis fans each of cfg.FilePaths out through analyzeFiles,
+// driving the progress view (interactive Bubble Tea or plain stdout lines)
+// for the duration, then builds a combined diff (for conversation storage),
+// a summarizer prompt from the per-file findings, and a file->hunks map the
+// summarizer's findings can be resolved against. ok is false if none of the
+// files had any changes, mirroring the old "nothing to do" exit.
+func runFileAnalysis(ctx context.Context, cfg Config, repo vcs.Repo, backend llm.Backend, agent *agents.Agent) (diffText string, summaryPrompt string, hunksByFile map[string][]vcs.Hunk, ok bool, err error) {
+	progress := make(chan fileProgress)
+
+	var results []fileResult
+	var pipelineErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		results, pipelineErr = analyzeFiles(ctx, cfg, repo, backend, agent, cfg.FilePaths, progress)
+	}()
+
+	if cfg.Interactive {
+		runProgressView(cfg.FilePaths, progress)
+	} else {
+		printProgress(progress)
+	}
+	<-done
+
+	if pipelineErr != nil {
+		return "", "", nil, false, pipelineErr
+	}
+
+	var diffs []string
+	var findings strings.Builder
+	hunksByFile = make(map[string][]vcs.Hunk)
+	anyChanges, anyFindings := false, false
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Printf("Error analyzing %s: %v\n", r.FilePath, r.Err)
+			continue
+		}
+		if r.Diff == "" {
+			continue
+		}
+		anyChanges = true
+		hunksByFile[r.FilePath] = r.Hunks
+		diffs = append(diffs, fmt.Sprintf("=== File: %s ===\n%s", r.FilePath, r.Diff))
+		if r.Analysis != "" {
+			anyFindings = true
+			fmt.Fprintf(&findings, "=== %s ===\n%s\n%s\n\n", r.FilePath, r.Analysis, hunkIndexList(r.FilePath, r.Hunks))
+		}
+	}
+	if !anyChanges {
+		return "", "", nil, false, nil
+	}
+
+	return strings.Join(diffs, "\n\n"), buildSummaryPrompt(findings.String(), anyFindings), hunksByFile, true, nil
+}
+
+// hunkIndexList renders filePath's hunks as an indexed reference list, so a
+// later structured-output pass can name a finding's location as a
+// hunk_index instead of a raw line number it would have to count itself.
+func hunkIndexList(filePath string, hunks []vcs.Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Diff hunks for %s (cite by index as hunk_index when reporting a finding's location):\n", filePath)
+	for i, h := range hunks {
+		fmt.Fprintf(&b, "[%d] @@ -%d,%d +%d,%d @@\n", i, h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	return b.String()
+}
+
+// buildSummaryPrompt turns the per-file analyses (each followed by its
+// indexed hunk list) into a single prompt asking the model to merge them
+// into one deduplicated report, catching any cross-file interactions the
+// independent per-file passes couldn't see.
+func buildSummaryPrompt(findings string, anyFindings bool) string {
+	var b strings.Builder
+	b.WriteString("You are an expert code reviewer. Below are independent per-file bug analyses produced for a single change set, each followed by that file's indexed diff hunks. Merge the analyses into one report: deduplicate overlapping findings across files, call out any cross-file interactions the individual analyses may have missed, and drop anything that turns out not to be a real issue. For each finding you keep, identify its location by the hunk_index of the diff hunk it belongs to, not a line number.\n\n")
+
+	if !anyFindings {
+		b.WriteString("Every per-file analysis below found no issues. Confirm that explicitly in your response; do not invent problems.\n\n")
+	}
+
+	fmt.Fprintf(&b, "Per-file analyses:\n%s", findings)
+	return b.String()
+}
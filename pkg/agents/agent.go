@@ -0,0 +1,63 @@
+// Package agents bundles a system prompt with an allowed toolset, so the
+// analyzer can swap between reviewer/security/perf personas that each look
+// at a diff through a different lens and pull in different context.
+package agents
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/crisecheverria/safeguard/pkg/tools"
+)
+
+// Agent is a persona the analyzer can adopt: a system prompt describing what
+// to look for, plus the subset of tools it's allowed to call.
+type Agent struct {
+	Name         string
+	SystemPrompt string
+	Tools        []tools.Tool
+}
+
+type factory func(toolset []tools.Tool) *Agent
+
+var registry = map[string]factory{
+	"reviewer": newReviewerAgent,
+	"security": newSecurityAgent,
+	"perf":     newPerfAgent,
+}
+
+// New builds the agent registered under name, sandboxing its tools to
+// repoRoot. If name isn't registered, the error lists the available ones.
+func New(name, repoRoot string) (*Agent, error) {
+	build, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q, available agents: %v", name, Names())
+	}
+	return build(tools.DefaultToolset(repoRoot)), nil
+}
+
+// Names returns the sorted list of registered agent personas.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// byName filters toolset down to the tools whose Name() is in names.
+func byName(toolset []tools.Tool, names ...string) []tools.Tool {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+
+	var filtered []tools.Tool
+	for _, t := range toolset {
+		if want[t.Name()] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,33 @@
+package agents
+
+import "github.com/crisecheverria/safeguard/pkg/tools"
+
+func newReviewerAgent(toolset []tools.Tool) *Agent {
+	return &Agent{
+		Name: "reviewer",
+		SystemPrompt: "You are an expert code reviewer. Analyze the diff for logic errors, edge cases, and " +
+			"API contract violations. Use the available tools to pull in the original definition of any " +
+			"modified function and to check how a changed API is used elsewhere before flagging an issue.",
+		Tools: toolset, // every tool is fair game for a general review
+	}
+}
+
+func newSecurityAgent(toolset []tools.Tool) *Agent {
+	return &Agent{
+		Name: "security",
+		SystemPrompt: "You are an application security reviewer. Focus exclusively on vulnerabilities: " +
+			"injection, unsafe deserialization, path traversal, auth/authz gaps, and secrets. Use read_file, " +
+			"search_symbol, and list_references to trace untrusted input to where it's used before reporting.",
+		Tools: byName(toolset, "read_file", "search_symbol", "list_references", "git_blame"),
+	}
+}
+
+func newPerfAgent(toolset []tools.Tool) *Agent {
+	return &Agent{
+		Name: "perf",
+		SystemPrompt: "You are a performance reviewer. Focus on algorithmic complexity, unnecessary " +
+			"allocations, blocking I/O on hot paths, and missing concurrency limits. Use run_tests to confirm " +
+			"a suspected regression still passes functionally before calling it out as a performance issue.",
+		Tools: byName(toolset, "read_file", "search_symbol", "run_tests"),
+	}
+}
@@ -0,0 +1,29 @@
+// Package conversations persists analysis sessions (the diff, prompt,
+// provider/model, and response) to a local sqlite database, so users can
+// reply to a saved session or fork it into a branch without regenerating
+// the diff.
+package conversations
+
+import "time"
+
+// Conversation is a single analysis session: the diff it was run against
+// plus the message history of follow-ups on top of it.
+type Conversation struct {
+	ID        int64
+	ParentID  *int64
+	Provider  string
+	Model     string
+	FilePaths []string
+	Diff      string
+	CreatedAt time.Time
+}
+
+// Message is one turn in a Conversation's history, starting with the
+// "user" prompt built from the diff and the "assistant" response to it.
+type Message struct {
+	ID             int64
+	ConversationID int64
+	Role           string
+	Content        string
+	CreatedAt      time.Time
+}
@@ -0,0 +1,243 @@
+package conversations
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS conversations (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	parent_id  INTEGER,
+	provider   TEXT NOT NULL,
+	model      TEXT NOT NULL,
+	file_paths TEXT NOT NULL,
+	diff       TEXT NOT NULL,
+	created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	conversation_id INTEGER NOT NULL REFERENCES conversations(id),
+	role            TEXT NOT NULL,
+	content         TEXT NOT NULL,
+	created_at      DATETIME NOT NULL
+);
+`
+
+// Store is a sqlite-backed conversation history under
+// ~/.local/share/safeguard/conversations.db.
+type Store struct {
+	db *sql.DB
+}
+
+// DBPath returns the path to the conversation database, expanding to the
+// user's home directory.
+func DBPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "safeguard", "conversations.db"), nil
+}
+
+// Open creates the database (and its parent directory) if needed and
+// returns a ready-to-use Store.
+func Open() (*Store, error) {
+	path, err := DBPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Create persists a new top-level conversation seeded with the diff prompt
+// and the backend's first response.
+func (s *Store) Create(provider, model string, filePaths []string, diff, prompt, response string) (*Conversation, error) {
+	now := time.Now()
+	res, err := s.db.Exec(
+		`INSERT INTO conversations (parent_id, provider, model, file_paths, diff, created_at) VALUES (NULL, ?, ?, ?, ?, ?)`,
+		provider, model, strings.Join(filePaths, ","), diff, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new conversation id: %w", err)
+	}
+
+	if err := s.addMessage(id, "user", prompt, now); err != nil {
+		return nil, err
+	}
+	if err := s.addMessage(id, "assistant", response, now); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// List returns every saved conversation, most recent first.
+func (s *Store) List() ([]Conversation, error) {
+	rows, err := s.db.Query(`SELECT id, parent_id, provider, model, file_paths, diff, created_at FROM conversations ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Conversation
+	for rows.Next() {
+		c, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Get returns a single conversation by id.
+func (s *Store) Get(id int64) (*Conversation, error) {
+	row := s.db.QueryRow(`SELECT id, parent_id, provider, model, file_paths, diff, created_at FROM conversations WHERE id = ?`, id)
+	c, err := scanConversation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no conversation with id %d", id)
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Messages returns a conversation's message history in chronological order.
+func (s *Store) Messages(id int64) ([]Message, error) {
+	rows, err := s.db.Query(`SELECT id, conversation_id, role, content, created_at FROM messages WHERE conversation_id = ? ORDER BY created_at ASC, id ASC`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load messages for conversation %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.Role, &m.Content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// AddMessage appends a message to an existing conversation (e.g. a reply
+// prompt or the response to it).
+func (s *Store) AddMessage(conversationID int64, role, content string) error {
+	return s.addMessage(conversationID, role, content, time.Now())
+}
+
+func (s *Store) addMessage(conversationID int64, role, content string, at time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO messages (conversation_id, role, content, created_at) VALUES (?, ?, ?, ?)`,
+		conversationID, role, content, at,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+	return nil
+}
+
+// Branch forks conversation id into a new conversation carrying the same
+// diff and a copy of its message history, so follow-ups on the branch leave
+// the original thread untouched. The messages are copied as they are; the
+// store has no operation to edit one.
+func (s *Store) Branch(id int64) (*Conversation, error) {
+	source, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.Messages(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res, err := s.db.Exec(
+		`INSERT INTO conversations (parent_id, provider, model, file_paths, diff, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		source.ID, source.Provider, source.Model, strings.Join(source.FilePaths, ","), source.Diff, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to branch conversation %d: %w", id, err)
+	}
+
+	branchID, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new conversation id: %w", err)
+	}
+
+	for _, m := range messages {
+		if err := s.addMessage(branchID, m.Role, m.Content, m.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Get(branchID)
+}
+
+// Delete removes a conversation and its messages.
+func (s *Store) Delete(id int64) error {
+	if _, err := s.db.Exec(`DELETE FROM messages WHERE conversation_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete messages for conversation %d: %w", id, err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM conversations WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete conversation %d: %w", id, err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanConversation(row rowScanner) (Conversation, error) {
+	var c Conversation
+	var filePaths string
+	var parentID sql.NullInt64
+
+	if err := row.Scan(&c.ID, &parentID, &c.Provider, &c.Model, &filePaths, &c.Diff, &c.CreatedAt); err != nil {
+		return Conversation{}, fmt.Errorf("failed to scan conversation: %w", err)
+	}
+
+	if parentID.Valid {
+		c.ParentID = &parentID.Int64
+	}
+	if filePaths != "" {
+		c.FilePaths = strings.Split(filePaths, ",")
+	}
+	return c, nil
+}
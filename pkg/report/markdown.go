@@ -0,0 +1,33 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMarkdown renders findings as a Markdown table, in the order the model
+// returned them.
+func ToMarkdown(findings []Finding) string {
+	if len(findings) == 0 {
+		return "No issues found.\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("| Level | File | Lines | Rule | Message |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "| %s | %s | %d-%d | %s | %s |\n", f.Level, f.File, f.StartLine, f.EndLine, escapeTableCell(f.RuleID), escapeTableCell(f.Message))
+	}
+	return b.String()
+}
+
+// escapeTableCell makes s safe to interpolate into a `|`-delimited Markdown
+// table cell: a literal `|` in an LLM-authored rule id or message (common in
+// code snippets like `a || b`) would otherwise be read as a column
+// separator and corrupt the row, and a newline would end it early.
+func escapeTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
@@ -0,0 +1,117 @@
+// Package report turns an LLM's structured findings into the machine-
+// readable formats CI systems expect: SARIF 2.1.0 for code-scanning
+// uploads, plain JSON, or a Markdown table.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/crisecheverria/safeguard/pkg/vcs"
+)
+
+// Finding is one fully-resolved issue, ready to render: its location has
+// already been cross-checked against the diff's actual hunks rather than
+// trusted as a raw line number the model reported.
+type Finding struct {
+	RuleID    string `json:"rule_id"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// findingsResponse is the shape ToJSON renders: a single top-level
+// "findings" array so a clean empty result isn't ambiguous with a parse
+// failure.
+type findingsResponse struct {
+	Findings []Finding `json:"findings"`
+}
+
+// RawFinding is what the model reports for one issue: a hunk index rather
+// than line numbers, since models are unreliable at counting lines but
+// reliable at pointing out which changed region a finding is about.
+// ResolveFindings turns these into Findings with real line ranges taken
+// from the diff's hunks.
+type RawFinding struct {
+	RuleID    string `json:"rule_id"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+	File      string `json:"file"`
+	HunkIndex int    `json:"hunk_index"`
+}
+
+type rawFindingsResponse struct {
+	Findings []RawFinding `json:"findings"`
+}
+
+// ParseRawFindings parses a model's structured-output response into raw
+// findings. A ```json code fence around the JSON, if present, is stripped
+// before unmarshaling.
+func ParseRawFindings(raw string) ([]RawFinding, error) {
+	raw = stripCodeFence(raw)
+
+	var resp rawFindingsResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse findings JSON: %w", err)
+	}
+	return resp.Findings, nil
+}
+
+func stripCodeFence(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "```") {
+		return raw
+	}
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	return strings.TrimSpace(raw)
+}
+
+// ResolveFindings turns raw findings into Findings by looking each one's
+// HunkIndex up in hunksByFile (keyed by the same file path the finding
+// names) and taking the real start/end lines from that hunk's position in
+// the target file, rather than trusting a line number the model invented. A
+// finding whose file or hunk index doesn't match any real hunk is kept,
+// pinned to line 1, so an LLM mistake drops the location rather than the
+// whole finding.
+func ResolveFindings(raw []RawFinding, hunksByFile map[string][]vcs.Hunk) []Finding {
+	findings := make([]Finding, 0, len(raw))
+	for _, r := range raw {
+		start, end := 1, 1
+		if hunks := hunksByFile[r.File]; r.HunkIndex >= 0 && r.HunkIndex < len(hunks) {
+			h := hunks[r.HunkIndex]
+			start = h.NewStart
+			end = h.NewStart + h.NewLines - 1
+			// A pure-deletion hunk reports NewStart as one before the first
+			// surviving line (down to 0 at the start of a file), per the
+			// unified-diff convention vcs.Hunk follows; SARIF and friends
+			// require line numbers >= 1, so pin to the nearest real line
+			// instead of emitting an invalid region.
+			if start < 1 {
+				start = 1
+			}
+			if end < start {
+				end = start
+			}
+		}
+		findings = append(findings, Finding{
+			RuleID:    r.RuleID,
+			Level:     r.Level,
+			Message:   r.Message,
+			File:      r.File,
+			StartLine: start,
+			EndLine:   end,
+		})
+	}
+	return findings
+}
+
+// ToJSON renders findings as the same {"findings": [...]} shape requested
+// from the model, pretty-printed for a CI log or artifact.
+func ToJSON(findings []Finding) ([]byte, error) {
+	return json.MarshalIndent(findingsResponse{Findings: findings}, "", "  ")
+}
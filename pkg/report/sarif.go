@@ -0,0 +1,115 @@
+package report
+
+import "encoding/json"
+
+// The sarif* types model the subset of SARIF 2.1.0 safeguard needs to
+// emit: one run, one tool driver, and a results array that GitHub code
+// scanning, GitLab SAST, and SonarQube can all ingest directly.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// sarifLevel maps a finding's level to SARIF's closed vocabulary,
+// defaulting to "warning" for anything the model didn't stick to.
+func sarifLevel(level string) string {
+	switch level {
+	case "error", "warning", "note":
+		return level
+	default:
+		return "warning"
+	}
+}
+
+// ToSARIF renders findings as a SARIF 2.1.0 log suitable for GitHub code
+// scanning, GitLab SAST, or SonarQube ingestion.
+func ToSARIF(findings []Finding) ([]byte, error) {
+	ruleSeen := make(map[string]bool)
+	var rules []sarifRule
+	results := make([]sarifResult, 0, len(findings))
+
+	for _, f := range findings {
+		if f.RuleID != "" && !ruleSeen[f.RuleID] {
+			ruleSeen[f.RuleID] = true
+			rules = append(rules, sarifRule{ID: f.RuleID})
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  f.RuleID,
+			Level:   sarifLevel(f.Level),
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.File},
+					Region:           sarifRegion{StartLine: f.StartLine, EndLine: f.EndLine},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "safeguard",
+				InformationURI: "https://github.com/crisecheverria/safeguard",
+				Version:        "1.0.0",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
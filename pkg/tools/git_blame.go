@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+type gitBlameTool struct {
+	sandbox
+}
+
+func newGitBlameTool(sb sandbox) Tool { return gitBlameTool{sb} }
+
+func (t gitBlameTool) Name() string { return "git_blame" }
+
+func (t gitBlameTool) Description() string {
+	return "Show who last changed a specific line of a file and in which commit."
+}
+
+func (t gitBlameTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file, relative to the repository root",
+			},
+			"line": map[string]interface{}{
+				"type":        "integer",
+				"description": "1-based line number to blame",
+			},
+		},
+		"required": []string{"path", "line"},
+	}
+}
+
+func (t gitBlameTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	path, err := stringArg(args, "path")
+	if err != nil {
+		return "", err
+	}
+	if _, err := t.resolvePath(path); err != nil {
+		return "", err
+	}
+
+	line, ok := args["line"].(float64)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a number", "line")
+	}
+
+	lineRange := fmt.Sprintf("%d,%d", int(line), int(line))
+	return t.run(ctx, "git", "blame", "-L", lineRange, "--", path)
+}
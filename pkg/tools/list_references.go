@@ -0,0 +1,44 @@
+package tools
+
+import "context"
+
+type listReferencesTool struct {
+	sandbox
+}
+
+func newListReferencesTool(sb sandbox) Tool { return listReferencesTool{sb} }
+
+func (t listReferencesTool) Name() string { return "list_references" }
+
+func (t listReferencesTool) Description() string {
+	return "List every file and line in the repository that references a given symbol."
+}
+
+func (t listReferencesTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"symbol": map[string]interface{}{
+				"type":        "string",
+				"description": "Symbol name to find references to",
+			},
+		},
+		"required": []string{"symbol"},
+	}
+}
+
+func (t listReferencesTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	symbol, err := stringArg(args, "symbol")
+	if err != nil {
+		return "", err
+	}
+
+	output, err := t.run(ctx, "git", "grep", "-n", "-w", symbol)
+	if err != nil {
+		return "", err
+	}
+	if output == "" {
+		return "no references found for " + symbol, nil
+	}
+	return output, nil
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type readFileTool struct {
+	sandbox
+}
+
+func newReadFileTool(sb sandbox) Tool { return readFileTool{sb} }
+
+func (t readFileTool) Name() string { return "read_file" }
+
+func (t readFileTool) Description() string {
+	return "Read the full contents of a file in the repository, given a path relative to the repo root."
+}
+
+func (t readFileTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file, relative to the repository root",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t readFileTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	path, err := stringArg(args, "path")
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := t.resolvePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,37 @@
+package tools
+
+import "context"
+
+type runTestsTool struct {
+	sandbox
+}
+
+func newRunTestsTool(sb sandbox) Tool { return runTestsTool{sb} }
+
+func (t runTestsTool) Name() string { return "run_tests" }
+
+func (t runTestsTool) Description() string {
+	return "Run the repository's Go tests matching a -run pattern and return their output."
+}
+
+func (t runTestsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "Regexp passed to 'go test -run'; use '.' to run everything",
+			},
+		},
+		"required": []string{"pattern"},
+	}
+}
+
+func (t runTestsTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	pattern, err := stringArg(args, "pattern")
+	if err != nil {
+		return "", err
+	}
+
+	return t.run(ctx, "go", "test", "./...", "-run", pattern)
+}
@@ -0,0 +1,45 @@
+package tools
+
+import "context"
+
+type searchSymbolTool struct {
+	sandbox
+}
+
+func newSearchSymbolTool(sb sandbox) Tool { return searchSymbolTool{sb} }
+
+func (t searchSymbolTool) Name() string { return "search_symbol" }
+
+func (t searchSymbolTool) Description() string {
+	return "Search the repository for the definition of a function, type, or variable by name."
+}
+
+func (t searchSymbolTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Symbol name to search for",
+			},
+		},
+		"required": []string{"name"},
+	}
+}
+
+func (t searchSymbolTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	name, err := stringArg(args, "name")
+	if err != nil {
+		return "", err
+	}
+
+	pattern := `^\s*(func|type|var|const)\s+\(?.*\b` + name + `\b`
+	output, err := t.run(ctx, "git", "grep", "-n", "-E", pattern)
+	if err != nil {
+		return "", err
+	}
+	if output == "" {
+		return "no definitions found for " + name, nil
+	}
+	return output, nil
+}
@@ -0,0 +1,114 @@
+// Package tools implements the sandboxed inspection tools an analysis agent
+// can invoke to pull context beyond the raw diff: reading files, searching
+// symbols, blaming lines, finding references, and running tests.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Tool is a single callable action exposed to an LLM backend. Name and
+// Parameters mirror both Anthropic's tool_use input_schema and OpenAI's
+// function-calling parameters, so a Tool can be translated to either wire
+// format without a provider-specific definition.
+type Tool interface {
+	Name() string
+	Description() string
+	// Parameters returns a JSON schema object (the {"type": "object", ...}
+	// shape) describing the tool's arguments.
+	Parameters() map[string]interface{}
+	// Execute runs the tool with args decoded from the model's tool call and
+	// returns the text to feed back as the tool result.
+	Execute(ctx context.Context, args map[string]interface{}) (string, error)
+}
+
+// sandbox resolves paths relative to a repo root and refuses to escape it,
+// since tool arguments come from model output and must not be trusted.
+type sandbox struct {
+	repoRoot string
+}
+
+func (s sandbox) resolvePath(path string) (string, error) {
+	abs := filepath.Join(s.repoRoot, path)
+	rel, err := filepath.Rel(s.repoRoot, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("path %q escapes the repository root", path)
+	}
+
+	// The lexical check above can be defeated by a symlink (committed in the
+	// repo, or introduced by the very diff being reviewed) that points
+	// outside repoRoot, so resolve both sides before the final containment
+	// check.
+	resolvedRoot, err := filepath.EvalSymlinks(s.repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository root: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %q: %w", path, err)
+	}
+	resolvedRel, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || strings.HasPrefix(resolvedRel, "..") {
+		return "", fmt.Errorf("path %q escapes the repository root", path)
+	}
+
+	return abs, nil
+}
+
+func (s sandbox) run(ctx context.Context, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = s.repoRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return "", fmt.Errorf("%s failed: %w", name, err)
+		}
+	}
+	return string(output), nil
+}
+
+func stringArg(args map[string]interface{}, name string) (string, error) {
+	v, ok := args[name]
+	if !ok {
+		return "", fmt.Errorf("missing required argument %q", name)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a string", name)
+	}
+	return s, nil
+}
+
+// DefaultToolset returns every tool sandboxed to repoRoot, in the order
+// personas typically want them presented.
+func DefaultToolset(repoRoot string) []Tool {
+	sb := sandbox{repoRoot: repoRoot}
+	return []Tool{
+		loggingTool{newReadFileTool(sb)},
+		loggingTool{newSearchSymbolTool(sb)},
+		loggingTool{newGitBlameTool(sb)},
+		loggingTool{newListReferencesTool(sb)},
+		loggingTool{newRunTestsTool(sb)},
+	}
+}
+
+// loggingTool wraps a Tool so every invocation is logged to stderr, keeping
+// tool calls visible to whoever is running safeguard even though the model
+// driving them isn't.
+type loggingTool struct {
+	Tool
+}
+
+func (t loggingTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	fmt.Fprintf(os.Stderr, "[tool] %s(%v)\n", t.Name(), args)
+	result, err := t.Tool.Execute(ctx, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[tool] %s error: %v\n", t.Name(), err)
+	}
+	return result, err
+}
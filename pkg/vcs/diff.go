@@ -0,0 +1,194 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContextLines is how many unchanged lines surround each hunk, matching
+// GNU diff -u's default.
+const diffContextLines = 3
+
+// lineOp is one line of a line-level diff, tagged with whether it's shared
+// by both sides or only one.
+type lineOp struct {
+	kind byte // ' ' equal, '-' removed, '+' added
+	text string
+}
+
+// UnifiedDiff renders a `diff -u --label`-style unified diff between source
+// and target, using a pure-Go line diff instead of shelling out to `diff`.
+func UnifiedDiff(source, target, sourceLabel, targetLabel string) string {
+	if source == target {
+		return ""
+	}
+
+	hunks := Hunks(source, target)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n", sourceLabel)
+	fmt.Fprintf(&out, "+++ %s\n", targetLabel)
+	for _, h := range hunks {
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, line := range h.Lines {
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+// Hunks computes the unified-diff hunks between source and target without
+// rendering them to text, so callers that need real line ranges (e.g.
+// resolving where a model's finding actually falls) can read them directly
+// instead of re-parsing UnifiedDiff's output.
+func Hunks(source, target string) []Hunk {
+	if source == target {
+		return nil
+	}
+	return buildHunks(lineDiff(source, target))
+}
+
+// lineDiff runs diffmatchpatch in line mode (each line collapsed to a
+// single rune for the LCS pass) and expands the result back into one lineOp
+// per source/target line.
+func lineDiff(source, target string) []lineOp {
+	dmp := diffmatchpatch.New()
+	text1, text2, lineArray := dmp.DiffLinesToChars(source, target)
+	diffs := dmp.DiffMain(text1, text2, false)
+	diffs = dmp.DiffCharsToLines(diffs, lineArray)
+
+	var ops []lineOp
+	for _, d := range diffs {
+		var kind byte
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			kind = ' '
+		case diffmatchpatch.DiffDelete:
+			kind = '-'
+		case diffmatchpatch.DiffInsert:
+			kind = '+'
+		}
+
+		lines := strings.Split(d.Text, "\n")
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, line := range lines {
+			ops = append(ops, lineOp{kind: kind, text: line})
+		}
+	}
+	return ops
+}
+
+// Hunk is one contiguous changed region of a unified diff: its position in
+// the old and new versions of the file, and the context/added/removed lines
+// in between (each prefixed with ' ', '-', or '+', matching UnifiedDiff's
+// rendered output).
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []string
+}
+
+// buildHunks groups lineOps into unified-diff hunks, keeping
+// diffContextLines of unchanged lines around each changed region and
+// merging regions whose context windows overlap.
+func buildHunks(ops []lineOp) []Hunk {
+	type span struct{ start, end int } // end exclusive, into ops
+
+	var changes []span
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(ops) && ops[j].kind != ' ' {
+			j++
+		}
+		changes = append(changes, span{i, j})
+		i = j
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var windows []span
+	for _, c := range changes {
+		start := c.start - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := c.end + diffContextLines
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if len(windows) > 0 && start <= windows[len(windows)-1].end {
+			if end > windows[len(windows)-1].end {
+				windows[len(windows)-1].end = end
+			}
+			continue
+		}
+		windows = append(windows, span{start, end})
+	}
+
+	oldLineAt := make([]int, len(ops)+1)
+	newLineAt := make([]int, len(ops)+1)
+	oldLine, newLine := 1, 1
+	for i, op := range ops {
+		oldLineAt[i] = oldLine
+		newLineAt[i] = newLine
+		switch op.kind {
+		case ' ':
+			oldLine++
+			newLine++
+		case '-':
+			oldLine++
+		case '+':
+			newLine++
+		}
+	}
+	oldLineAt[len(ops)] = oldLine
+	newLineAt[len(ops)] = newLine
+
+	hunks := make([]Hunk, 0, len(windows))
+	for _, w := range windows {
+		var h Hunk
+		for i := w.start; i < w.end; i++ {
+			op := ops[i]
+			h.Lines = append(h.Lines, string(op.kind)+op.text)
+			switch op.kind {
+			case ' ':
+				h.OldLines++
+				h.NewLines++
+			case '-':
+				h.OldLines++
+			case '+':
+				h.NewLines++
+			}
+		}
+
+		// Unified diff convention (matching GNU diff -u): a side with zero
+		// lines reports its position as one less than the first surviving
+		// line on that side, e.g. "@@ -0,0 +1,2 @@" for an insertion at the
+		// start of an empty file, not "@@ -1,0 ...".
+		h.OldStart = oldLineAt[w.start]
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		h.NewStart = newLineAt[w.start]
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
@@ -0,0 +1,39 @@
+package vcs
+
+import "testing"
+
+func TestUnifiedDiffInsertIntoEmptyFile(t *testing.T) {
+	got := UnifiedDiff("", "hello\nworld\n", "a", "b")
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+hello\n+world\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestUnifiedDiffDeleteToEmptyFile(t *testing.T) {
+	got := UnifiedDiff("hello\nworld\n", "", "a", "b")
+	want := "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-hello\n-world\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestUnifiedDiffMergesOverlappingContext(t *testing.T) {
+	src := "l1\nl2\nl3\nl4\nl5\nl6\nl7\nl8\nl9\nl10\n"
+	tgt := "l1\nl2X\nl3\nl4\nl5\nl6\nl7\nl8\nl9X\nl10\n"
+	got := UnifiedDiff(src, tgt, "a", "b")
+	want := "--- a\n+++ b\n@@ -1,10 +1,10 @@\n l1\n-l2\n+l2X\n l3\n l4\n l5\n l6\n l7\n l8\n-l9\n+l9X\n l10\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestUnifiedDiffKeepsDistantHunksSeparate(t *testing.T) {
+	src := "l1\nl2\nl3\nl4\nl5\nl6\nl7\nl8\nl9\nl10\nl11\nl12\nl13\nl14\nl15\nl16\nl17\nl18\nl19\nl20\n"
+	tgt := "l1\nl2X\nl3\nl4\nl5\nl6\nl7\nl8\nl9\nl10\nl11\nl12\nl13\nl14\nl15\nl16\nl17\nl18X\nl19\nl20\n"
+	got := UnifiedDiff(src, tgt, "a", "b")
+	want := "--- a\n+++ b\n@@ -1,5 +1,5 @@\n l1\n-l2\n+l2X\n l3\n l4\n l5\n@@ -15,6 +15,6 @@\n l15\n l16\n l17\n-l18\n+l18X\n l19\n l20\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
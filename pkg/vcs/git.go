@@ -0,0 +1,131 @@
+package vcs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GitRepo is a Repo backed by an on-disk git repository via go-git, with no
+// dependency on a `git` binary being present in PATH.
+type GitRepo struct {
+	repo *git.Repository
+}
+
+// Open finds and opens the git repository containing path, searching parent
+// directories for a .git folder the way the git CLI does.
+func Open(path string) (*GitRepo, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository at %s: %w", path, err)
+	}
+	return &GitRepo{repo: repo}, nil
+}
+
+func (r *GitRepo) resolveCommit(revision string) (*object.Commit, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %q: %w", revision, err)
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commit for revision %q: %w", revision, err)
+	}
+	return commit, nil
+}
+
+func (r *GitRepo) ListFiles() ([]string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load HEAD commit: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tree at HEAD: %w", err)
+	}
+
+	var files []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func (r *GitRepo) ReadFile(path, revision string) (string, error) {
+	commit, err := r.resolveCommit(revision)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to load tree for %q: %w", revision, err)
+	}
+
+	file, err := tree.File(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s at %s: %w", path, revision, err)
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", path, revision, err)
+	}
+	return content, nil
+}
+
+func (r *GitRepo) Diff(path, sourceRevision, targetRevision string) (string, error) {
+	sourceContent, targetContent, err := r.diffContents(path, sourceRevision, targetRevision)
+	if err != nil {
+		return "", err
+	}
+
+	sourceLabel := fmt.Sprintf("%s:%s", sourceRevision, path)
+	targetLabel := fmt.Sprintf("%s:%s", targetRevision, path)
+	return UnifiedDiff(sourceContent, targetContent, sourceLabel, targetLabel), nil
+}
+
+func (r *GitRepo) Hunks(path, sourceRevision, targetRevision string) ([]Hunk, error) {
+	sourceContent, targetContent, err := r.diffContents(path, sourceRevision, targetRevision)
+	if err != nil {
+		return nil, err
+	}
+	return Hunks(sourceContent, targetContent), nil
+}
+
+func (r *GitRepo) DiffAndHunks(path, sourceRevision, targetRevision string) (string, []Hunk, error) {
+	sourceContent, targetContent, err := r.diffContents(path, sourceRevision, targetRevision)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sourceLabel := fmt.Sprintf("%s:%s", sourceRevision, path)
+	targetLabel := fmt.Sprintf("%s:%s", targetRevision, path)
+	return UnifiedDiff(sourceContent, targetContent, sourceLabel, targetLabel), Hunks(sourceContent, targetContent), nil
+}
+
+func (r *GitRepo) diffContents(path, sourceRevision, targetRevision string) (sourceContent, targetContent string, err error) {
+	sourceContent, err = r.ReadFile(path, sourceRevision)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting file %s from %s: %w", path, sourceRevision, err)
+	}
+
+	targetContent, err = r.ReadFile(path, targetRevision)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting file %s from %s: %w", path, targetRevision, err)
+	}
+	return sourceContent, targetContent, nil
+}
@@ -0,0 +1,25 @@
+// Package vcs abstracts over the version-control operations safeguard needs
+// — listing tracked files, reading a file as of some revision, and diffing
+// a file between two revisions — behind a Repo interface, so a future
+// Mercurial or Jujutsu backend could slot in alongside the git one.
+package vcs
+
+// Repo is implemented by every version-control backend safeguard can read
+// diffs from.
+type Repo interface {
+	// ListFiles returns every file tracked at HEAD.
+	ListFiles() ([]string, error)
+	// ReadFile returns path's contents as of revision (a branch, tag,
+	// commit hash, or relative revspec like "HEAD~3").
+	ReadFile(path, revision string) (string, error)
+	// Diff returns a unified diff of path between sourceRevision and
+	// targetRevision, or "" if they're identical.
+	Diff(path, sourceRevision, targetRevision string) (string, error)
+	// Hunks returns the same changed regions as Diff, parsed into
+	// structured ranges instead of rendered text, so callers needing real
+	// line numbers don't have to re-parse a diff they already generated.
+	Hunks(path, sourceRevision, targetRevision string) ([]Hunk, error)
+	// DiffAndHunks returns the same result as calling Diff and Hunks, but
+	// reads and diffs path only once, for callers that need both.
+	DiffAndHunks(path, sourceRevision, targetRevision string) (diff string, hunks []Hunk, err error)
+}
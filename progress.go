@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// progressModel renders per-file pipeline status (queued/diffing/analyzing/
+// done/skipped/error) as files move through analyzeFiles, reusing the
+// title/selection styling fileModel already established.
+type progressModel struct {
+	spinner  spinner.Model
+	order    []string
+	statuses map[string]fileProgress
+	updates  <-chan fileProgress
+	closed   bool
+}
+
+type progressMsg struct {
+	update fileProgress
+	ok     bool
+}
+
+// runProgressView drives a Bubble Tea progress display off updates until the
+// channel closes, listing filePaths in the order analysis was requested.
+func runProgressView(filePaths []string, updates <-chan fileProgress) {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	m := progressModel{
+		spinner:  sp,
+		order:    filePaths,
+		statuses: make(map[string]fileProgress, len(filePaths)),
+		updates:  updates,
+	}
+
+	p := tea.NewProgram(m)
+	p.Run()
+
+	// If the view was quit early, keep draining so analyzeFiles never blocks
+	// on a send nobody receives.
+	for range updates {
+	}
+}
+
+// printProgress is the non-interactive fallback: one line per file as it
+// finishes, skipped, or errors, instead of a live-redrawing view.
+func printProgress(updates <-chan fileProgress) {
+	for u := range updates {
+		switch u.Status {
+		case statusDone:
+			fmt.Printf("  done: %s\n", u.FilePath)
+		case statusSkipped:
+			fmt.Printf("  no changes: %s\n", u.FilePath)
+		case statusError:
+			fmt.Printf("  error: %s: %v\n", u.FilePath, u.Err)
+		}
+	}
+}
+
+func waitForProgress(updates <-chan fileProgress) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		return progressMsg{update: update, ok: ok}
+	}
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, waitForProgress(m.updates))
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case progressMsg:
+		if !msg.ok {
+			m.closed = true
+			return m, tea.Quit
+		}
+		m.statuses[msg.update.FilePath] = msg.update
+		return m, waitForProgress(m.updates)
+
+	case spinner.TickMsg:
+		if m.closed {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m progressModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#0000FF")).
+		Bold(true).
+		Padding(0, 1)
+
+	doneStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+	pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Analyzing files") + "\n\n")
+
+	for _, path := range m.order {
+		status, ok := m.statuses[path]
+		if !ok {
+			status = fileProgress{FilePath: path, Status: statusQueued}
+		}
+
+		var marker string
+		switch status.Status {
+		case statusDone:
+			marker = doneStyle.Render("done")
+		case statusSkipped:
+			marker = pendingStyle.Render("skip")
+		case statusError:
+			marker = errorStyle.Render("fail")
+		case statusQueued:
+			marker = pendingStyle.Render("wait")
+		default:
+			marker = m.spinner.View()
+		}
+
+		line := fmt.Sprintf("%s  %s  %s", marker, path, status.Status)
+		if status.Status == statusError && status.Err != nil {
+			line += fmt.Sprintf(" (%v)", status.Err)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if m.closed {
+		b.WriteString("\nDone.\n")
+	}
+
+	return b.String()
+}